@@ -0,0 +1,116 @@
+// Package config loads the runtime configuration for the service from
+// environment variables, optionally overlaid on top of a JSON file.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds all runtime configuration for the service.
+type Config struct {
+	ListenAddr      string        `json:"listen_addr"`
+	DatabaseURL     string        `json:"database_url"`
+	TLSCert         string        `json:"tls_cert"`
+	TLSKey          string        `json:"tls_key"`
+	MaxOpenConns    int           `json:"max_open_conns"`
+	MaxIdleConns    int           `json:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+	DefaultPageSize int           `json:"default_page_size"`
+	MaxPageSize     int           `json:"max_page_size"`
+}
+
+// defaults mirrors the hard-coded values the service used before it became
+// config-driven.
+func defaults() Config {
+	return Config{
+		ListenAddr:      ":8080",
+		DatabaseURL:     "host=localhost port=5432 user=rustemsharipov dbname=todo_db sslmode=disable",
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+		ShutdownTimeout: 10 * time.Second,
+		DefaultPageSize: 20,
+		MaxPageSize:     100,
+	}
+}
+
+// Load builds a Config, applying a JSON file named by the CONFIG_FILE
+// environment variable (if set) and then individual environment variables
+// on top of the defaults, in that order of increasing precedence.
+func Load() (Config, error) {
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, fmt.Errorf("load config file %s: %w", path, err)
+		}
+	}
+
+	loadEnv(&cfg)
+
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, cfg)
+}
+
+func loadEnv(cfg *Config) {
+	if v := os.Getenv("LISTEN_ADDR"); v != "" {
+		cfg.ListenAddr = v
+	}
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		cfg.DatabaseURL = v
+	}
+	if v := os.Getenv("TLS_CERT"); v != "" {
+		cfg.TLSCert = v
+	}
+	if v := os.Getenv("TLS_KEY"); v != "" {
+		cfg.TLSKey = v
+	}
+	if v := os.Getenv("MAX_OPEN_CONNS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MaxOpenConns = n
+		}
+	}
+	if v := os.Getenv("MAX_IDLE_CONNS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MaxIdleConns = n
+		}
+	}
+	if v := os.Getenv("CONN_MAX_LIFETIME"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.ConnMaxLifetime = d
+		}
+	}
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.ShutdownTimeout = d
+		}
+	}
+	if v := os.Getenv("DEFAULT_PAGE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.DefaultPageSize = n
+		}
+	}
+	if v := os.Getenv("MAX_PAGE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MaxPageSize = n
+		}
+	}
+}
+
+// TLSEnabled reports whether both a certificate and key were configured,
+// in which case the server should listen with TLS instead of plain HTTP.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCert != "" && c.TLSKey != ""
+}
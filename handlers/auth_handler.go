@@ -0,0 +1,72 @@
+// Package handlers contains the HTTP layer: request decoding, response
+// encoding, and status-code mapping. Business logic lives in services.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+// AuthHandler exposes registration and login over HTTP.
+type AuthHandler struct {
+	auth *services.AuthService
+}
+
+// NewAuthHandler constructs an AuthHandler backed by auth.
+func NewAuthHandler(auth *services.AuthService) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
+
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var input models.RegisterInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.auth.Register(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			http.Error(w, "Email already registered", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, services.ErrMissingFields) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logError(r.Context(), "failed to register user", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input models.LoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.auth.Login(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+			return
+		}
+		logError(r.Context(), "failed to log in user", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/metrics"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+type contextKey int
+
+const (
+	userIDContextKey contextKey = iota
+	requestIDContextKey
+)
+
+// AuthMiddleware validates credentials on every request and attaches the
+// authenticated user's ID to the request context. It accepts either an
+// `Authorization: Bearer <token>` header or a `User`/`Auth` header pair
+// (the User header names the user ID the Auth token must resolve to),
+// preferring Bearer when both are present. It returns 401 when no
+// credentials were supplied and 403 when the supplied credentials are
+// invalid.
+func AuthMiddleware(auth *services.AuthService) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token, declaredUserID := credentialsFromRequest(r)
+
+			userID, err := auth.Authenticate(r.Context(), token)
+			if err == nil && declaredUserID != "" && strconv.Itoa(userID) != declaredUserID {
+				err = services.ErrInvalidToken
+			}
+			if err != nil {
+				switch {
+				case errors.Is(err, services.ErrMissingCredentials):
+					http.Error(w, "Missing credentials", http.StatusUnauthorized)
+				case errors.Is(err, services.ErrInvalidToken):
+					http.Error(w, "Invalid credentials", http.StatusForbidden)
+				default:
+					logError(r.Context(), "failed to authenticate request", err)
+					http.Error(w, "Internal server error", http.StatusInternalServerError)
+				}
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// credentialsFromRequest extracts the session token from the request,
+// along with the User header's declared user ID when the header-pair
+// scheme is used (declaredUserID is empty for the Bearer scheme, which
+// carries no separate user claim to cross-check).
+func credentialsFromRequest(r *http.Request) (token, declaredUserID string) {
+	if token := bearerToken(r); token != "" {
+		return token, ""
+	}
+	return r.Header.Get("Auth"), r.Header.Get("User")
+}
+
+// bearerToken extracts the token from the Authorization: Bearer <token> header.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(header, prefix))
+}
+
+// userIDFromContext returns the authenticated user's ID, set by AuthMiddleware.
+func userIDFromContext(ctx context.Context) int {
+	userID, _ := ctx.Value(userIDContextKey).(int)
+	return userID
+}
+
+// requestIDFromContext returns the current request's ID, set by Observe.
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// logError logs a handler-level failure as structured JSON, tagged with
+// the request ID set by Observe.
+func logError(ctx context.Context, msg string, err error) {
+	slog.ErrorContext(ctx, msg, "error", err, "request_id", requestIDFromContext(ctx))
+}
+
+// statusRecorder wraps a ResponseWriter to capture the status code written,
+// for access logging and metrics.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Observe assigns each request an ID, logs its outcome as structured JSON,
+// and records it under route in the http_requests_total and
+// http_request_duration_seconds metrics. It wraps each API route, ahead of
+// any per-route middleware such as AuthMiddleware; the health and metrics
+// routes are not wrapped.
+func Observe(route string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			requestID := newRequestID()
+			w.Header().Set("X-Request-Id", requestID)
+			ctx := context.WithValue(r.Context(), requestIDContextKey, requestID)
+			r = r.WithContext(ctx)
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next(rec, r)
+			duration := time.Since(start)
+
+			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(duration.Seconds())
+
+			slog.InfoContext(ctx, "http request",
+				"request_id", requestID,
+				"method", r.Method,
+				"route", route,
+				"status", rec.status,
+				"duration_ms", duration.Milliseconds(),
+			)
+		}
+	}
+}
+
+// newRequestID returns a short random hex string used to correlate log
+// lines for a single request.
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository/repotest"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+func newTestAuthService(t *testing.T) (*services.AuthService, int, string) {
+	t.Helper()
+	auth := services.NewAuthService(repotest.NewFakeUserRepo(), repotest.NewFakeSessionRepo())
+
+	user, err := auth.Register(context.Background(), models.RegisterInput{Email: "dave@example.com", Password: "pw"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	token, err := auth.Login(context.Background(), models.LoginInput{Email: "dave@example.com", Password: "pw"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return auth, user.ID, token
+}
+
+func authMiddlewareProbe() (http.HandlerFunc, *int) {
+	var gotUserID int
+	return func(w http.ResponseWriter, r *http.Request) {
+		gotUserID = userIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}, &gotUserID
+}
+
+func TestAuthMiddleware_Bearer(t *testing.T) {
+	auth, userID, token := newTestAuthService(t)
+	next, gotUserID := authMiddlewareProbe()
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+
+	AuthMiddleware(auth)(next)(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if *gotUserID != userID {
+		t.Errorf("userID = %d, want %d", *gotUserID, userID)
+	}
+}
+
+func TestAuthMiddleware_UserAuthHeaderPair(t *testing.T) {
+	auth, userID, token := newTestAuthService(t)
+	next, gotUserID := authMiddlewareProbe()
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("User", strconv.Itoa(userID))
+	req.Header.Set("Auth", token)
+	rec := httptest.NewRecorder()
+
+	AuthMiddleware(auth)(next)(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if *gotUserID != userID {
+		t.Errorf("userID = %d, want %d", *gotUserID, userID)
+	}
+}
+
+func TestAuthMiddleware_UserAuthHeaderPairMismatch(t *testing.T) {
+	auth, _, token := newTestAuthService(t)
+	next, _ := authMiddlewareProbe()
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("User", "999")
+	req.Header.Set("Auth", token)
+	rec := httptest.NewRecorder()
+
+	AuthMiddleware(auth)(next)(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestAuthMiddleware_MissingCredentials(t *testing.T) {
+	auth, _, _ := newTestAuthService(t)
+	next, _ := authMiddlewareProbe()
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	rec := httptest.NewRecorder()
+
+	AuthMiddleware(auth)(next)(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddleware_InvalidToken(t *testing.T) {
+	auth, _, _ := newTestAuthService(t)
+	next, _ := authMiddlewareProbe()
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("Authorization", "Bearer not-a-real-token")
+	rec := httptest.NewRecorder()
+
+	AuthMiddleware(auth)(next)(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+// todoPageResponse is the JSON envelope returned by GET /todos.
+type todoPageResponse struct {
+	Items      []models.Todo `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Total      *int          `json:"total,omitempty"`
+}
+
+// TodoHandler exposes todo CRUD over HTTP.
+type TodoHandler struct {
+	todos           *services.TodoService
+	defaultPageSize int
+	maxPageSize     int
+}
+
+// NewTodoHandler constructs a TodoHandler backed by todos. defaultPageSize
+// is used when the caller omits ?limit=, and maxPageSize caps it, both
+// configurable so an operator can tune them without a rebuild.
+func NewTodoHandler(todos *services.TodoService, defaultPageSize, maxPageSize int) *TodoHandler {
+	return &TodoHandler{todos: todos, defaultPageSize: defaultPageSize, maxPageSize: maxPageSize}
+}
+
+// WithID adapts a handler that needs the numeric {id} path value, extracted
+// via Go's net/http pattern routing.
+func WithID(next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+		next(w, r, id)
+	}
+}
+
+func (h *TodoHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	filter := models.TodoFilter{Only: q.Get("only")}
+	switch filter.Only {
+	case "", "active", "completed":
+	default:
+		http.Error(w, "Invalid only parameter, must be 'active' or 'completed'", http.StatusBadRequest)
+		return
+	}
+
+	if completedParam := q.Get("completed"); completedParam != "" {
+		completed, err := strconv.ParseBool(completedParam)
+		if err != nil {
+			http.Error(w, "Invalid completed parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Completed = &completed
+	}
+
+	if dueBefore := q.Get("due_before"); dueBefore != "" {
+		t, err := time.Parse(time.RFC3339, dueBefore)
+		if err != nil {
+			http.Error(w, "Invalid due_before parameter, must be RFC3339", http.StatusBadRequest)
+			return
+		}
+		filter.DueBefore = &t
+	}
+
+	if dueAfter := q.Get("due_after"); dueAfter != "" {
+		t, err := time.Parse(time.RFC3339, dueAfter)
+		if err != nil {
+			http.Error(w, "Invalid due_after parameter, must be RFC3339", http.StatusBadRequest)
+			return
+		}
+		filter.DueAfter = &t
+	}
+
+	filter.Tag = q.Get("tag")
+	filter.Cursor = q.Get("cursor")
+	filter.IncludeTotal = q.Get("include_total") == "true"
+
+	filter.Limit = h.defaultPageSize
+	if limitParam := q.Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	if filter.Limit > h.maxPageSize {
+		filter.Limit = h.maxPageSize
+	}
+
+	if offsetParam := q.Get("offset"); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	if sortParam := q.Get("sort"); sortParam != "" {
+		column, desc, err := parseSort(sortParam)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.SortColumn = column
+		filter.SortDesc = desc
+	}
+
+	page, err := h.todos.List(r.Context(), userID, filter)
+	if err != nil {
+		logError(r.Context(), "failed to list todos", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todoPageResponse{
+		Items:      page.Items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+// parseSort validates a "column[:asc|desc]" sort parameter against the
+// repository's column allow-list.
+func parseSort(raw string) (column string, desc bool, err error) {
+	column = raw
+	direction := "asc"
+	if idx := strings.Index(raw, ":"); idx != -1 {
+		column = raw[:idx]
+		direction = raw[idx+1:]
+	}
+
+	if !repository.TodoSortColumns[column] {
+		return "", false, fmt.Errorf("invalid sort column %q", column)
+	}
+
+	switch direction {
+	case "asc":
+		return column, false, nil
+	case "desc":
+		return column, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid sort direction %q", direction)
+	}
+}
+
+func (h *TodoHandler) Get(w http.ResponseWriter, r *http.Request, id int) {
+	userID := userIDFromContext(r.Context())
+
+	todo, err := h.todos.Get(r.Context(), userID, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Todo not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logError(r.Context(), "failed to get todo", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	tag := etag(todo)
+	w.Header().Set("ETag", tag)
+	if inm := r.Header.Get("If-None-Match"); inm != "" && inm == tag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todo)
+}
+
+func (h *TodoHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+
+	var input models.TodoCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.todos.Create(r.Context(), userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTitleRequired), errors.Is(err, services.ErrDueDateInPast):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			logError(r.Context(), "failed to create todo", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(todo)
+}
+
+// Update handles both PUT and PATCH /todos/{id}: TodoUpdate's pointer
+// fields already make this a partial update, so both methods share it. An
+// If-Match header, if present, must match the todo's current ETag or the
+// write is rejected with 412 Precondition Failed.
+func (h *TodoHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	userID := userIDFromContext(r.Context())
+
+	var input models.TodoUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ifMatch, err := ifMatchTime(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.todos.Update(r.Context(), userID, id, input, ifMatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			http.Error(w, "Todo not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrPreconditionFailed):
+			http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		case errors.Is(err, repository.ErrNoFields):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrDueDateInPast):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			logError(r.Context(), "failed to update todo", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", etag(todo))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todo)
+}
+
+// Delete honors an If-Match header the same way Update does, so a client
+// can't delete a todo out from under a concurrent edit it hasn't seen yet.
+func (h *TodoHandler) Delete(w http.ResponseWriter, r *http.Request, id int) {
+	userID := userIDFromContext(r.Context())
+
+	ifMatch, err := ifMatchTime(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.todos.Delete(r.Context(), userID, id, ifMatch)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Todo not found", http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrPreconditionFailed):
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+		return
+	case err != nil:
+		logError(r.Context(), "failed to delete todo", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// etag computes the weak ETag for a todo from its updated_at timestamp.
+func etag(t models.Todo) string {
+	return fmt.Sprintf(`W/"%d"`, t.UpdatedAt.UnixNano())
+}
+
+// parseETag extracts the updated_at timestamp encoded in a weak ETag
+// produced by etag.
+func parseETag(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) < 4 || !strings.HasPrefix(raw, `W/"`) || !strings.HasSuffix(raw, `"`) {
+		return time.Time{}, false
+	}
+	ns, err := strconv.ParseInt(raw[3:len(raw)-1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
+
+// ifMatchTime parses the If-Match header, if present, into the updated_at
+// value it encodes so repositories can enforce optimistic concurrency.
+func ifMatchTime(r *http.Request) (*time.Time, error) {
+	raw := r.Header.Get("If-Match")
+	if raw == "" {
+		return nil, nil
+	}
+	t, ok := parseETag(raw)
+	if !ok {
+		return nil, fmt.Errorf("invalid If-Match header")
+	}
+	return &t, nil
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/repository/repotest"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+// newTestHandler wires a TodoHandler to an in-memory fake repository, so
+// these tests exercise the handler's error-to-status mapping without a
+// real database.
+func newTestHandler(t *testing.T, fake *repotest.FakeTodoRepo) *TodoHandler {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	svc := services.NewTodoService(db, func(repository.DB) repository.TodoRepository { return fake })
+	return NewTodoHandler(svc, 20, 100)
+}
+
+func withUserID(r *http.Request, userID int) *http.Request {
+	ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+	return r.WithContext(ctx)
+}
+
+func TestTodoHandler_Get_ETagAndIfNoneMatch(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	seeded := fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	h := newTestHandler(t, fake)
+
+	req := withUserID(httptest.NewRequest(http.MethodGet, "/todos/1", nil), 1)
+	rec := httptest.NewRecorder()
+	h.Get(rec, req, 1)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	wantETag := `W/"` + strconv.FormatInt(seeded.UpdatedAt.UnixNano(), 10) + `"`
+	if got := rec.Header().Get("ETag"); got != wantETag {
+		t.Fatalf("ETag = %q, want %q", got, wantETag)
+	}
+
+	req = withUserID(httptest.NewRequest(http.MethodGet, "/todos/1", nil), 1)
+	req.Header.Set("If-None-Match", wantETag)
+	rec = httptest.NewRecorder()
+	h.Get(rec, req, 1)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestTodoHandler_Update_NotFound(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	h := newTestHandler(t, fake)
+
+	req := withUserID(httptest.NewRequest(http.MethodPut, "/todos/999", strings.NewReader(`{"title":"x"}`)), 1)
+	rec := httptest.NewRecorder()
+
+	h.Update(rec, req, 999)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestTodoHandler_Update_PreconditionFailed(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	seeded := fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	h := newTestHandler(t, fake)
+
+	stale := seeded.UpdatedAt.Add(-time.Minute)
+	req := httptest.NewRequest(http.MethodPut, "/todos/1", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("If-Match", `W/"`+strconv.FormatInt(stale.UnixNano(), 10)+`"`)
+	req = withUserID(req, 1)
+	rec := httptest.NewRecorder()
+
+	h.Update(rec, req, 1)
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestTodoHandler_Delete_NotFound(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	h := newTestHandler(t, fake)
+
+	req := withUserID(httptest.NewRequest(http.MethodDelete, "/todos/999", nil), 1)
+	rec := httptest.NewRecorder()
+
+	h.Delete(rec, req, 999)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestTodoHandler_Delete_PreconditionFailed(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	seeded := fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	h := newTestHandler(t, fake)
+
+	stale := seeded.UpdatedAt.Add(-time.Minute)
+	req := httptest.NewRequest(http.MethodDelete, "/todos/1", nil)
+	req.Header.Set("If-Match", `W/"`+strconv.FormatInt(stale.UnixNano(), 10)+`"`)
+	req = withUserID(req, 1)
+	rec := httptest.NewRecorder()
+
+	h.Delete(rec, req, 1)
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestParseETag(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		ok   bool
+	}{
+		{name: "valid", raw: `W/"123"`, ok: true},
+		{name: "empty", raw: "", ok: false},
+		{name: "truncated overlapping quotes", raw: `W/"`, ok: false},
+		{name: "empty value", raw: `W/""`, ok: false},
+		{name: "not a number", raw: `W/"abc"`, ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ok := parseETag(c.raw)
+			if ok != c.ok {
+				t.Errorf("parseETag(%q) ok = %v, want %v", c.raw, ok, c.ok)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_Update_MalformedIfMatchDoesNotPanic(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	h := newTestHandler(t, fake)
+
+	req := httptest.NewRequest(http.MethodPut, "/todos/1", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("If-Match", `W/"`)
+	req = withUserID(req, 1)
+	rec := httptest.NewRecorder()
+
+	h.Update(rec, req, 1)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -1,410 +1,137 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
-	"strconv"
-	"strings"
-	"time"
+	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/lib/pq"
-)
-
-// Todo represents a todo item
-type Todo struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
-// TodoCreate represents the input for creating a todo
-type TodoCreate struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-}
 
-// TodoUpdate represents the input for updating a todo
-type TodoUpdate struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Completed   *bool   `json:"completed,omitempty"`
-}
+	"github.com/sharipovr/todo-crud-postgres/config"
+	"github.com/sharipovr/todo-crud-postgres/handlers"
+	"github.com/sharipovr/todo-crud-postgres/metrics"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
 
 // Database connection pool
 var db *sql.DB
 
 func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize database connection
-	var err error
-	connStr := "host=localhost port=5432 user=rustemsharipov dbname=todo_db sslmode=disable"
-	db, err = sql.Open("postgres", connStr)
+	db, err = sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
-		log.Fatal("Failed to open database connection:", err)
+		logger.Error("failed to open database connection", "error", err)
+		os.Exit(1)
 	}
 	defer db.Close()
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	// Test database connection
 	if err := db.Ping(); err != nil {
-		log.Fatal("Failed to ping database:", err)
+		logger.Error("failed to ping database", "error", err)
+		os.Exit(1)
 	}
+	logger.Info("connected to PostgreSQL database")
 
-	log.Println("Successfully connected to PostgreSQL database")
-
-	// Setup HTTP routes
-	http.HandleFunc("/todos", todosHandler)
-	http.HandleFunc("/todos/", todoHandler)
-	http.HandleFunc("/health", healthHandler)
+	// Wire up repositories, services, and handlers
+	todoService := services.NewTodoService(db, func(d repository.DB) repository.TodoRepository {
+		return repository.NewTodoRepo(repository.Instrument(d))
+	})
+	authService := services.NewAuthService(
+		repository.NewUserRepo(repository.Instrument(db)),
+		repository.NewSessionRepo(repository.Instrument(db)),
+	)
 
-	// Start server
-	log.Println("Server starting on :8080...")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatal("Server failed to start:", err)
-	}
-}
-
-// healthHandler checks if the server and database are healthy
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+	todoHandler := handlers.NewTodoHandler(todoService, cfg.DefaultPageSize, cfg.MaxPageSize)
+	authHandler := handlers.NewAuthHandler(authService)
+	requireAuth := handlers.AuthMiddleware(authService)
 
-	if err := db.Ping(); err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]string{"status": "unhealthy", "error": err.Error()})
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
-}
-
-// todosHandler handles /todos endpoint (GET all, POST new)
-func todosHandler(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		getTodos(w, r)
-	case http.MethodPost:
-		createTodo(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-// todoHandler handles /todos/{id} endpoint (GET, PUT, DELETE)
-func todoHandler(w http.ResponseWriter, r *http.Request) {
-	// Extract ID from path
-	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(pathParts) != 2 {
-		http.Error(w, "Invalid path", http.StatusBadRequest)
-		return
-	}
-
-	id, err := strconv.Atoi(pathParts[1])
-	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return
-	}
-
-	switch r.Method {
-	case http.MethodGet:
-		getTodoByID(w, r, id)
-	case http.MethodPut:
-		updateTodo(w, r, id)
-	case http.MethodDelete:
-		deleteTodo(w, r, id)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Setup HTTP routes
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /register", handlers.Observe("POST /register")(authHandler.Register))
+	mux.HandleFunc("POST /login", handlers.Observe("POST /login")(authHandler.Login))
+	mux.HandleFunc("GET /todos", handlers.Observe("GET /todos")(requireAuth(todoHandler.List)))
+	mux.HandleFunc("POST /todos", handlers.Observe("POST /todos")(requireAuth(todoHandler.Create)))
+	mux.HandleFunc("GET /todos/{id}", handlers.Observe("GET /todos/{id}")(requireAuth(handlers.WithID(todoHandler.Get))))
+	mux.HandleFunc("PUT /todos/{id}", handlers.Observe("PUT /todos/{id}")(requireAuth(handlers.WithID(todoHandler.Update))))
+	mux.HandleFunc("PATCH /todos/{id}", handlers.Observe("PATCH /todos/{id}")(requireAuth(handlers.WithID(todoHandler.Update))))
+	mux.HandleFunc("DELETE /todos/{id}", handlers.Observe("DELETE /todos/{id}")(requireAuth(handlers.WithID(todoHandler.Delete))))
+	mux.HandleFunc("GET /health/live", livenessHandler)
+	mux.HandleFunc("GET /health/ready", readinessHandler)
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	srv := &http.Server{
+		Addr:    cfg.ListenAddr,
+		Handler: mux,
 	}
-}
-
-// getTodos returns all todos with optional filtering
-func getTodos(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters for filtering
-	completedParam := r.URL.Query().Get("completed")
-
-	var query string
-	var args []interface{}
 
-	if completedParam != "" {
-		completed, err := strconv.ParseBool(completedParam)
-		if err != nil {
-			http.Error(w, "Invalid completed parameter", http.StatusBadRequest)
-			return
+	// Start server
+	go func() {
+		logger.Info("server starting", "addr", cfg.ListenAddr, "tls", cfg.TLSEnabled())
+
+		var err error
+		if cfg.TLSEnabled() {
+			err = srv.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey)
+		} else {
+			err = srv.ListenAndServe()
 		}
-		query = "SELECT id, title, description, completed, created_at, updated_at FROM todos WHERE completed = $1 ORDER BY created_at DESC"
-		args = append(args, completed)
-	} else {
-		query = "SELECT id, title, description, completed, created_at, updated_at FROM todos ORDER BY created_at DESC"
-	}
-
-	// Use prepared statement
-	stmt, err := db.Prepare(query)
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
-
-	rows, err := stmt.Query(args...)
-	if err != nil {
-		log.Printf("Failed to query todos: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	todos := make([]Todo, 0)
-	for rows.Next() {
-		var todo Todo
-		err := rows.Scan(&todo.ID, &todo.Title, &todo.Description, &todo.Completed, &todo.CreatedAt, &todo.UpdatedAt)
-		if err != nil {
-			log.Printf("Failed to scan todo: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server failed to start", "error", err)
+			os.Exit(1)
 		}
-		todos = append(todos, todo)
-	}
-
-	if err := rows.Err(); err != nil {
-		log.Printf("Error iterating rows: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(todos)
-}
+	}()
 
-// getTodoByID returns a single todo by ID
-func getTodoByID(w http.ResponseWriter, r *http.Request, id int) {
-	query := "SELECT id, title, description, completed, created_at, updated_at FROM todos WHERE id = $1"
-
-	stmt, err := db.Prepare(query)
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
+	// Wait for SIGINT/SIGTERM, then drain in-flight requests before exiting.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
 
-	var todo Todo
-	err = stmt.QueryRow(id).Scan(&todo.ID, &todo.Title, &todo.Description, &todo.Completed, &todo.CreatedAt, &todo.UpdatedAt)
-	if err == sql.ErrNoRows {
-		http.Error(w, "Todo not found", http.StatusNotFound)
-		return
+	logger.Info("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("graceful shutdown failed", "error", err)
 	}
-	if err != nil {
-		log.Printf("Failed to query todo: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(todo)
 }
 
-// createTodo creates a new todo
-func createTodo(w http.ResponseWriter, r *http.Request) {
-	var input TodoCreate
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validate input
-	if strings.TrimSpace(input.Title) == "" {
-		http.Error(w, "Title is required", http.StatusBadRequest)
-		return
-	}
-
-	// Start transaction
-	tx, err := db.Begin()
-	if err != nil {
-		log.Printf("Failed to begin transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer tx.Rollback() // Will be ignored if transaction is committed
-
-	// Use prepared statement within transaction
-	query := "INSERT INTO todos (title, description, completed, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at"
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
-
-	now := time.Now()
-	var todo Todo
-	todo.Title = input.Title
-	todo.Description = input.Description
-	todo.Completed = false
-
-	err = stmt.QueryRow(todo.Title, todo.Description, todo.Completed, now, now).Scan(&todo.ID, &todo.CreatedAt, &todo.UpdatedAt)
-	if err != nil {
-		log.Printf("Failed to insert todo: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		log.Printf("Failed to commit transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
+// livenessHandler reports whether the process itself is up. It never
+// touches the database, so a database outage can't fail a liveness probe
+// and trigger an unnecessary restart.
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(todo)
+	json.NewEncoder(w).Encode(map[string]string{"status": "alive"})
 }
 
-// updateTodo updates an existing todo
-func updateTodo(w http.ResponseWriter, r *http.Request, id int) {
-	var input TodoUpdate
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Build dynamic update query
-	updates := make([]string, 0)
-	args := make([]interface{}, 0)
-	argPos := 1
-
-	if input.Title != nil {
-		updates = append(updates, fmt.Sprintf("title = $%d", argPos))
-		args = append(args, *input.Title)
-		argPos++
-	}
-	if input.Description != nil {
-		updates = append(updates, fmt.Sprintf("description = $%d", argPos))
-		args = append(args, *input.Description)
-		argPos++
-	}
-	if input.Completed != nil {
-		updates = append(updates, fmt.Sprintf("completed = $%d", argPos))
-		args = append(args, *input.Completed)
-		argPos++
-	}
-
-	if len(updates) == 0 {
-		http.Error(w, "No fields to update", http.StatusBadRequest)
-		return
-	}
-
-	// Always update updated_at
-	updates = append(updates, fmt.Sprintf("updated_at = $%d", argPos))
-	args = append(args, time.Now())
-	argPos++
-
-	// Add ID to args
-	args = append(args, id)
-
-	// Start transaction
-	tx, err := db.Begin()
-	if err != nil {
-		log.Printf("Failed to begin transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	// Build and execute update query
-	query := fmt.Sprintf("UPDATE todos SET %s WHERE id = $%d RETURNING id, title, description, completed, created_at, updated_at",
-		strings.Join(updates, ", "), argPos)
-
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
-
-	var todo Todo
-	err = stmt.QueryRow(args...).Scan(&todo.ID, &todo.Title, &todo.Description, &todo.Completed, &todo.CreatedAt, &todo.UpdatedAt)
-	if err == sql.ErrNoRows {
-		http.Error(w, "Todo not found", http.StatusNotFound)
-		return
-	}
-	if err != nil {
-		log.Printf("Failed to update todo: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		log.Printf("Failed to commit transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+// readinessHandler reports whether the service can currently serve
+// traffic, gated on a successful database ping.
+func readinessHandler(w http.ResponseWriter, r *http.Request) {
+	if err := db.PingContext(r.Context()); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "unhealthy", "error": err.Error()})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(todo)
-}
-
-// deleteTodo deletes a todo by ID
-func deleteTodo(w http.ResponseWriter, r *http.Request, id int) {
-	// Start transaction
-	tx, err := db.Begin()
-	if err != nil {
-		log.Printf("Failed to begin transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	query := "DELETE FROM todos WHERE id = $1"
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
-
-	result, err := stmt.Exec(id)
-	if err != nil {
-		log.Printf("Failed to delete todo: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Printf("Failed to get rows affected: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	if rowsAffected == 0 {
-		http.Error(w, "Todo not found", http.StatusNotFound)
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		log.Printf("Failed to commit transaction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
 }
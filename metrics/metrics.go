@@ -0,0 +1,40 @@
+// Package metrics defines the Prometheus instrumentation exposed by the
+// service and the HTTP handler that serves it.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// HTTPRequestsTotal counts completed HTTP requests by method, route,
+	// and response status code.
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests processed.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration tracks HTTP request latency by method and route.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// DBQueryDuration tracks database query latency, labeled by the
+	// query's leading SQL keyword (select/insert/update/delete).
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+)
+
+// Handler serves the current metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Todo represents a todo item owned by a user.
+type Todo struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"-"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TodoCreate represents the input for creating a todo.
+type TodoCreate struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags"`
+}
+
+// TodoUpdate represents the input for updating a todo. Nil fields are left untouched.
+type TodoUpdate struct {
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Completed   *bool      `json:"completed,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// TodoFilter captures the optional filters and pagination settings accepted
+// by TodoRepository.List.
+type TodoFilter struct {
+	Only      string // "", "active", "completed"
+	Completed *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Tag       string
+
+	// SortColumn is one of the allow-listed columns (created_at, updated_at,
+	// due_date); SortDesc reverses the direction. Ignored when Cursor is set.
+	SortColumn string
+	SortDesc   bool
+	Limit      int
+	Offset     int
+
+	// Cursor, when non-empty, switches List into keyset pagination mode:
+	// Offset/SortColumn/SortDesc are ignored and results are ordered by
+	// (created_at, id) descending, starting after the decoded cursor, at
+	// most Limit per page.
+	Cursor string
+
+	IncludeTotal bool
+}
+
+// TodoPage is the result of a paginated TodoRepository.List call.
+type TodoPage struct {
+	Items      []Todo
+	NextCursor string
+	Total      *int
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// User represents a registered account.
+type User struct {
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RegisterInput represents the input for creating an account.
+type RegisterInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginInput represents the input for exchanging credentials for a token.
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EncodeCursor packs (created_at, id) into an opaque keyset pagination
+// token. It is exported so repotest.FakeTodoRepo can emulate the same
+// cursor format as TodoRepo.
+func EncodeCursor(createdAt time.Time, id int) string {
+	raw := fmt.Sprintf("%s,%d", createdAt.Format(time.RFC3339Nano), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var id int
+	if _, err := fmt.Sscanf(parts[1], "%d", &id); err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return createdAt, id, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	id := 42
+
+	cursor := EncodeCursor(createdAt, id)
+
+	gotCreatedAt, gotID, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !gotCreatedAt.Equal(createdAt) {
+		t.Errorf("created_at = %v, want %v", gotCreatedAt, createdAt)
+	}
+	if gotID != id {
+		t.Errorf("id = %d, want %d", gotID, id)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"not-base64!!!",
+		base64.RawURLEncoding.EncodeToString([]byte("no-comma-here")),
+		base64.RawURLEncoding.EncodeToString([]byte("not-a-time,42")),
+	}
+
+	for _, cursor := range cases {
+		if _, _, err := DecodeCursor(cursor); err == nil {
+			t.Errorf("DecodeCursor(%q): want error, got nil", cursor)
+		}
+	}
+}
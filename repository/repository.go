@@ -0,0 +1,75 @@
+// Package repository contains the data-access layer. Each repository talks
+// to Postgres directly over database/sql; services depend on the
+// interfaces here so they can be swapped for fakes in tests.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/metrics"
+)
+
+// DB is the subset of *sql.DB/*sql.Tx operations repositories need, so the
+// same repository implementation can run inside or outside a transaction.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// instrumentedDB wraps a DB so every query it runs is timed and reported
+// via the metrics package.
+type instrumentedDB struct {
+	DB
+}
+
+// Instrument wraps db so its queries are recorded in the
+// db_query_duration_seconds metric. It accepts a *sql.DB or a *sql.Tx, so
+// callers can instrument both ordinary and transactional repositories.
+func Instrument(db DB) DB {
+	return instrumentedDB{DB: db}
+}
+
+func (d instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer observeQuery(query, time.Now())
+	return d.DB.ExecContext(ctx, query, args...)
+}
+
+func (d instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer observeQuery(query, time.Now())
+	return d.DB.QueryContext(ctx, query, args...)
+}
+
+func (d instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer observeQuery(query, time.Now())
+	return d.DB.QueryRowContext(ctx, query, args...)
+}
+
+// observeQuery records how long a query took, labeled by its leading SQL
+// keyword (select/insert/update/delete).
+func observeQuery(query string, start time.Time) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return
+	}
+	metrics.DBQueryDuration.WithLabelValues(strings.ToLower(fields[0])).Observe(time.Since(start).Seconds())
+}
+
+// WithTx runs fn inside a transaction, committing if fn returns nil and
+// rolling back otherwise. Callers pass the resulting tx to their
+// repositories to scope a unit of work.
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
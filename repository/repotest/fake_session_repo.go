@@ -0,0 +1,29 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+)
+
+// FakeSessionRepo is an in-memory repository.SessionRepository.
+type FakeSessionRepo struct {
+	userIDByToken map[string]int
+}
+
+// NewFakeSessionRepo returns an empty FakeSessionRepo.
+func NewFakeSessionRepo() *FakeSessionRepo {
+	return &FakeSessionRepo{userIDByToken: make(map[string]int)}
+}
+
+func (f *FakeSessionRepo) Create(ctx context.Context, token string, userID int) error {
+	f.userIDByToken[token] = userID
+	return nil
+}
+
+func (f *FakeSessionRepo) UserIDForToken(ctx context.Context, token string) (int, error) {
+	userID, ok := f.userIDByToken[token]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return userID, nil
+}
@@ -0,0 +1,252 @@
+// Package repotest provides in-memory fakes of the repository interfaces,
+// for services/handlers tests that want to exercise business logic without
+// a real Postgres connection.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+)
+
+// FakeTodoRepo is an in-memory repository.TodoRepository. It mirrors
+// TodoRepo's not-found/precondition-failed behavior so callers can be
+// tested against it without a database.
+type FakeTodoRepo struct {
+	Todos  map[int]models.Todo
+	nextID int
+}
+
+// NewFakeTodoRepo returns an empty FakeTodoRepo.
+func NewFakeTodoRepo() *FakeTodoRepo {
+	return &FakeTodoRepo{Todos: make(map[int]models.Todo), nextID: 1}
+}
+
+// Seed inserts a todo directly, bypassing Create, for test setup.
+func (f *FakeTodoRepo) Seed(todo models.Todo) models.Todo {
+	f.Todos[todo.ID] = todo
+	if todo.ID >= f.nextID {
+		f.nextID = todo.ID + 1
+	}
+	return todo
+}
+
+// List mirrors TodoRepo.List's filtering, sorting, and pagination
+// semantics closely enough to exercise handler/service code against it:
+// Only/Completed/DueBefore/DueAfter/Tag narrow the result set, SortColumn
+// (validated against repository.TodoSortColumns) and SortDesc order it
+// unless Cursor is set, in which case it switches to keyset pagination
+// over (created_at, id) descending, and IncludeTotal reports the
+// pre-pagination count.
+func (f *FakeTodoRepo) List(ctx context.Context, userID int, filter models.TodoFilter) (models.TodoPage, error) {
+	if filter.SortColumn != "" && !repository.TodoSortColumns[filter.SortColumn] {
+		return models.TodoPage{}, repository.ErrInvalidSortColumn
+	}
+
+	var items []models.Todo
+	for _, t := range f.Todos {
+		if t.UserID != userID {
+			continue
+		}
+		switch filter.Only {
+		case "active":
+			if t.Completed {
+				continue
+			}
+		case "completed":
+			if !t.Completed {
+				continue
+			}
+		}
+		if filter.Completed != nil && t.Completed != *filter.Completed {
+			continue
+		}
+		if filter.DueBefore != nil && (t.DueDate == nil || !t.DueDate.Before(*filter.DueBefore)) {
+			continue
+		}
+		if filter.DueAfter != nil && (t.DueDate == nil || !t.DueDate.After(*filter.DueAfter)) {
+			continue
+		}
+		if filter.Tag != "" && !hasTag(t.Tags, filter.Tag) {
+			continue
+		}
+		items = append(items, t)
+	}
+
+	var page models.TodoPage
+	if filter.IncludeTotal {
+		total := len(items)
+		page.Total = &total
+	}
+
+	if filter.Cursor != "" {
+		sortByCreatedAtIDDesc(items)
+
+		cursorCreatedAt, cursorID, err := repository.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return models.TodoPage{}, err
+		}
+		items = afterCursor(items, cursorCreatedAt, cursorID)
+
+		if len(items) > filter.Limit {
+			last := items[filter.Limit-1]
+			page.NextCursor = repository.EncodeCursor(last.CreatedAt, last.ID)
+			items = items[:filter.Limit]
+		}
+		page.Items = items
+		return page, nil
+	}
+
+	sortColumn := filter.SortColumn
+	if sortColumn == "" {
+		sortColumn = "created_at"
+	}
+	sortItems(items, sortColumn, filter.SortDesc)
+
+	items = paginate(items, filter.Limit, filter.Offset)
+	page.Items = items
+	return page, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByCreatedAtIDDesc(items []models.Todo) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID > items[j].ID
+	})
+}
+
+// afterCursor returns the items strictly after (createdAt, id) in the
+// (created_at, id) descending order afterCursor's caller already sorted by.
+func afterCursor(items []models.Todo, createdAt time.Time, id int) []models.Todo {
+	var out []models.Todo
+	for _, t := range items {
+		if t.CreatedAt.Before(createdAt) || (t.CreatedAt.Equal(createdAt) && t.ID < id) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func sortItems(items []models.Todo, column string, desc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		var less bool
+		switch column {
+		case "updated_at":
+			less = items[i].UpdatedAt.Before(items[j].UpdatedAt)
+		case "due_date":
+			less = dueDateBefore(items[i].DueDate, items[j].DueDate)
+		default:
+			less = items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		if desc {
+			return !less
+		}
+		return less
+	})
+}
+
+// dueDateBefore treats a nil due date as sorting after any set date. Since
+// sortItems inverts the comparison for descending order, nil due dates come
+// last when ascending and first when descending, as Postgres orders NULLs
+// by default.
+func dueDateBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
+
+func paginate(items []models.Todo, limit, offset int) []models.Todo {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func (f *FakeTodoRepo) GetByID(ctx context.Context, userID, id int) (models.Todo, error) {
+	t, ok := f.Todos[id]
+	if !ok || t.UserID != userID {
+		return models.Todo{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+func (f *FakeTodoRepo) Create(ctx context.Context, userID int, input models.TodoCreate) (models.Todo, error) {
+	now := time.Now()
+	todo := models.Todo{
+		ID:          f.nextID,
+		UserID:      userID,
+		Title:       input.Title,
+		Description: input.Description,
+		DueDate:     input.DueDate,
+		Tags:        input.Tags,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	f.nextID++
+	f.Todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (f *FakeTodoRepo) Update(ctx context.Context, userID, id int, input models.TodoUpdate, ifMatch *time.Time) (models.Todo, error) {
+	t, ok := f.Todos[id]
+	if !ok || t.UserID != userID {
+		return models.Todo{}, sql.ErrNoRows
+	}
+	if ifMatch != nil && !t.UpdatedAt.Equal(*ifMatch) {
+		return models.Todo{}, repository.ErrPreconditionFailed
+	}
+
+	if input.Title != nil {
+		t.Title = *input.Title
+	}
+	if input.Description != nil {
+		t.Description = *input.Description
+	}
+	if input.Completed != nil {
+		t.Completed = *input.Completed
+	}
+	if input.DueDate != nil {
+		t.DueDate = input.DueDate
+	}
+	if input.Tags != nil {
+		t.Tags = input.Tags
+	}
+	t.UpdatedAt = time.Now()
+	f.Todos[id] = t
+	return t, nil
+}
+
+func (f *FakeTodoRepo) Delete(ctx context.Context, userID, id int, ifMatch *time.Time) error {
+	t, ok := f.Todos[id]
+	if !ok || t.UserID != userID {
+		return sql.ErrNoRows
+	}
+	if ifMatch != nil && !t.UpdatedAt.Equal(*ifMatch) {
+		return repository.ErrPreconditionFailed
+	}
+	delete(f.Todos, id)
+	return nil
+}
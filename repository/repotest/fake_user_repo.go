@@ -0,0 +1,45 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+)
+
+// FakeUserRepo is an in-memory repository.UserRepository.
+type FakeUserRepo struct {
+	byEmail map[string]models.User
+	nextID  int
+}
+
+// NewFakeUserRepo returns an empty FakeUserRepo.
+func NewFakeUserRepo() *FakeUserRepo {
+	return &FakeUserRepo{byEmail: make(map[string]models.User), nextID: 1}
+}
+
+func (f *FakeUserRepo) Create(ctx context.Context, email, passwordHash string) (models.User, error) {
+	if _, ok := f.byEmail[email]; ok {
+		return models.User{}, repository.ErrEmailTaken
+	}
+
+	user := models.User{
+		ID:           f.nextID,
+		Email:        email,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}
+	f.nextID++
+	f.byEmail[email] = user
+	return user, nil
+}
+
+func (f *FakeUserRepo) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	user, ok := f.byEmail[email]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
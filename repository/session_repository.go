@@ -0,0 +1,30 @@
+package repository
+
+import "context"
+
+// SessionRepository persists login sessions keyed by opaque token.
+type SessionRepository interface {
+	Create(ctx context.Context, token string, userID int) error
+	UserIDForToken(ctx context.Context, token string) (int, error)
+}
+
+// SessionRepo is a SessionRepository backed by Postgres.
+type SessionRepo struct {
+	db DB
+}
+
+// NewSessionRepo constructs a SessionRepo bound to db.
+func NewSessionRepo(db DB) *SessionRepo {
+	return &SessionRepo{db: db}
+}
+
+func (r *SessionRepo) Create(ctx context.Context, token string, userID int) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO sessions (token, user_id) VALUES ($1, $2)", token, userID)
+	return err
+}
+
+func (r *SessionRepo) UserIDForToken(ctx context.Context, token string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, "SELECT user_id FROM sessions WHERE token = $1", token).Scan(&userID)
+	return userID, err
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+)
+
+// ErrNoFields is returned by Update when the input has no fields set.
+var ErrNoFields = errors.New("no fields to update")
+
+// ErrPreconditionFailed is returned by Update and Delete when the caller
+// supplied an ifMatch timestamp but the row's current updated_at no longer
+// matches it.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrInvalidSortColumn is returned by List when filter.SortColumn is set
+// but isn't in TodoSortColumns.
+var ErrInvalidSortColumn = errors.New("invalid sort column")
+
+// TodoRepository persists and queries todos. Handlers depend on this
+// interface (not the concrete type) so they can be tested against an
+// in-memory fake.
+type TodoRepository interface {
+	List(ctx context.Context, userID int, filter models.TodoFilter) (models.TodoPage, error)
+	GetByID(ctx context.Context, userID, id int) (models.Todo, error)
+	Create(ctx context.Context, userID int, input models.TodoCreate) (models.Todo, error)
+	// Update applies input's set fields. When ifMatch is non-nil, the
+	// write only takes effect if the row's updated_at still equals it;
+	// otherwise it returns ErrPreconditionFailed.
+	Update(ctx context.Context, userID, id int, input models.TodoUpdate, ifMatch *time.Time) (models.Todo, error)
+	// Delete removes the row. When ifMatch is non-nil, the delete only
+	// takes effect if the row's updated_at still equals it; otherwise it
+	// returns ErrPreconditionFailed.
+	Delete(ctx context.Context, userID, id int, ifMatch *time.Time) error
+}
+
+// TodoSortColumns is the allow-list of columns TodoFilter.SortColumn may
+// name, to keep the ORDER BY clause free of unsanitized user input.
+var TodoSortColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"due_date":   true,
+}
+
+// TodoRepo is a TodoRepository backed by Postgres. It accepts any DB handle
+// (a *sql.DB or a *sql.Tx) so services can scope it to a transaction.
+type TodoRepo struct {
+	db DB
+}
+
+// NewTodoRepo constructs a TodoRepo bound to db.
+func NewTodoRepo(db DB) *TodoRepo {
+	return &TodoRepo{db: db}
+}
+
+func (r *TodoRepo) List(ctx context.Context, userID int, filter models.TodoFilter) (models.TodoPage, error) {
+	if filter.SortColumn != "" && !TodoSortColumns[filter.SortColumn] {
+		return models.TodoPage{}, ErrInvalidSortColumn
+	}
+
+	conditions := []string{"user_id = $1"}
+	args := []interface{}{userID}
+
+	switch filter.Only {
+	case "active":
+		conditions = append(conditions, "completed = false")
+	case "completed":
+		conditions = append(conditions, "completed = true")
+	}
+
+	if filter.Completed != nil {
+		args = append(args, *filter.Completed)
+		conditions = append(conditions, fmt.Sprintf("completed = $%d", len(args)))
+	}
+	if filter.DueBefore != nil {
+		args = append(args, *filter.DueBefore)
+		conditions = append(conditions, fmt.Sprintf("due_date < $%d", len(args)))
+	}
+	if filter.DueAfter != nil {
+		args = append(args, *filter.DueAfter)
+		conditions = append(conditions, fmt.Sprintf("due_date > $%d", len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, pq.Array([]string{filter.Tag}))
+		conditions = append(conditions, fmt.Sprintf("tags && $%d", len(args)))
+	}
+
+	var page models.TodoPage
+	if filter.IncludeTotal {
+		var total int
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM todos WHERE %s", strings.Join(conditions, " AND "))
+		if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+			return models.TodoPage{}, err
+		}
+		page.Total = &total
+	}
+
+	if filter.Cursor != "" {
+		cursorCreatedAt, cursorID, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return models.TodoPage{}, err
+		}
+		args = append(args, cursorCreatedAt, cursorID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	where := strings.Join(conditions, " AND ")
+	limit := filter.Limit
+
+	var query string
+	if filter.Cursor != "" {
+		query = fmt.Sprintf(
+			"SELECT id, title, description, completed, due_date, tags, created_at, updated_at FROM todos WHERE %s ORDER BY created_at DESC, id DESC LIMIT %d",
+			where, limit+1,
+		)
+	} else {
+		sortColumn := filter.SortColumn
+		if sortColumn == "" {
+			sortColumn = "created_at"
+		}
+		direction := "ASC"
+		if filter.SortDesc {
+			direction = "DESC"
+		}
+		query = fmt.Sprintf(
+			"SELECT id, title, description, completed, due_date, tags, created_at, updated_at FROM todos WHERE %s ORDER BY %s %s LIMIT %d OFFSET %d",
+			where, sortColumn, direction, limit, filter.Offset,
+		)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.TodoPage{}, err
+	}
+	defer rows.Close()
+
+	todos := make([]models.Todo, 0)
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return models.TodoPage{}, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return models.TodoPage{}, err
+	}
+
+	if filter.Cursor != "" && len(todos) > limit {
+		last := todos[limit-1]
+		page.NextCursor = EncodeCursor(last.CreatedAt, last.ID)
+		todos = todos[:limit]
+	}
+
+	page.Items = todos
+	return page, nil
+}
+
+func (r *TodoRepo) GetByID(ctx context.Context, userID, id int) (models.Todo, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT id, title, description, completed, due_date, tags, created_at, updated_at FROM todos WHERE id = $1 AND user_id = $2",
+		id, userID,
+	)
+	return scanTodo(row)
+}
+
+func (r *TodoRepo) Create(ctx context.Context, userID int, input models.TodoCreate) (models.Todo, error) {
+	var todo models.Todo
+	todo.UserID = userID
+	todo.Title = input.Title
+	todo.Description = input.Description
+	todo.DueDate = input.DueDate
+	todo.Tags = input.Tags
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx,
+		"INSERT INTO todos (user_id, title, description, completed, due_date, tags, created_at, updated_at) VALUES ($1, $2, $3, false, $4, $5, $6, $7) RETURNING id, created_at, updated_at",
+		todo.UserID, todo.Title, todo.Description, todo.DueDate, pq.Array(todo.Tags), now, now,
+	).Scan(&todo.ID, &todo.CreatedAt, &todo.UpdatedAt)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	return todo, nil
+}
+
+func (r *TodoRepo) Update(ctx context.Context, userID, id int, input models.TodoUpdate, ifMatch *time.Time) (models.Todo, error) {
+	updates := make([]string, 0)
+	args := make([]interface{}, 0)
+	argPos := 1
+
+	if input.Title != nil {
+		updates = append(updates, fmt.Sprintf("title = $%d", argPos))
+		args = append(args, *input.Title)
+		argPos++
+	}
+	if input.Description != nil {
+		updates = append(updates, fmt.Sprintf("description = $%d", argPos))
+		args = append(args, *input.Description)
+		argPos++
+	}
+	if input.Completed != nil {
+		updates = append(updates, fmt.Sprintf("completed = $%d", argPos))
+		args = append(args, *input.Completed)
+		argPos++
+	}
+	if input.DueDate != nil {
+		updates = append(updates, fmt.Sprintf("due_date = $%d", argPos))
+		args = append(args, *input.DueDate)
+		argPos++
+	}
+	if input.Tags != nil {
+		updates = append(updates, fmt.Sprintf("tags = $%d", argPos))
+		args = append(args, pq.Array(input.Tags))
+		argPos++
+	}
+
+	if len(updates) == 0 {
+		return models.Todo{}, ErrNoFields
+	}
+
+	updates = append(updates, fmt.Sprintf("updated_at = $%d", argPos))
+	args = append(args, time.Now())
+	argPos++
+
+	args = append(args, id, userID)
+	where := fmt.Sprintf("id = $%d AND user_id = $%d", argPos, argPos+1)
+	argPos += 2
+
+	if ifMatch != nil {
+		args = append(args, *ifMatch)
+		where += fmt.Sprintf(" AND updated_at = $%d", argPos)
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE todos SET %s WHERE %s RETURNING id, title, description, completed, due_date, tags, created_at, updated_at",
+		strings.Join(updates, ", "), where,
+	)
+
+	row := r.db.QueryRowContext(ctx, query, args...)
+	todo, err := scanTodo(row)
+	if err == sql.ErrNoRows && ifMatch != nil {
+		return models.Todo{}, r.conflictOrNotFound(ctx, userID, id)
+	}
+	return todo, err
+}
+
+func (r *TodoRepo) Delete(ctx context.Context, userID, id int, ifMatch *time.Time) error {
+	query := "DELETE FROM todos WHERE id = $1 AND user_id = $2"
+	args := []interface{}{id, userID}
+	if ifMatch != nil {
+		query += " AND updated_at = $3"
+		args = append(args, *ifMatch)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if ifMatch != nil {
+			return r.conflictOrNotFound(ctx, userID, id)
+		}
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// conflictOrNotFound distinguishes, after a conditional write matched zero
+// rows, whether the row is simply gone (sql.ErrNoRows) or still exists with
+// a different updated_at (ErrPreconditionFailed).
+func (r *TodoRepo) conflictOrNotFound(ctx context.Context, userID, id int) error {
+	if _, err := r.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+	return ErrPreconditionFailed
+}
+
+// scanTodo scans a single todo row, including the nullable due_date column.
+func scanTodo(row interface{ Scan(...interface{}) error }) (models.Todo, error) {
+	var todo models.Todo
+	var dueDate sql.NullTime
+	err := row.Scan(&todo.ID, &todo.Title, &todo.Description, &todo.Completed, &dueDate, pq.Array(&todo.Tags), &todo.CreatedAt, &todo.UpdatedAt)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	if dueDate.Valid {
+		todo.DueDate = &dueDate.Time
+	}
+	return todo, nil
+}
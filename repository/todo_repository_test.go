@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+)
+
+func TestTodoRepo_List_InvalidSortColumnRejectedWithoutQuerying(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewTodoRepo(db)
+	_, err = repo.List(context.Background(), 1, models.TodoFilter{SortColumn: "password_hash"})
+	if !errors.Is(err, ErrInvalidSortColumn) {
+		t.Fatalf("want ErrInvalidSortColumn, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no queries to run, but: %v", err)
+	}
+}
+
+func TestTodoRepo_List_OrdersByRequestedColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "completed", "due_date", "tags", "created_at", "updated_at"})
+	mock.ExpectQuery(`SELECT .* FROM todos WHERE user_id = \$1 ORDER BY due_date DESC LIMIT 20 OFFSET 0`).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	repo := NewTodoRepo(db)
+	_, err = repo.List(context.Background(), 1, models.TodoFilter{SortColumn: "due_date", SortDesc: true, Limit: 20})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestTodoRepo_List_CursorModeOrdersByCreatedAtID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	cursor := EncodeCursor(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 5)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "completed", "due_date", "tags", "created_at", "updated_at"})
+	mock.ExpectQuery(`SELECT .* FROM todos WHERE user_id = \$1 AND \(created_at, id\) < \(\$2, \$3\) ORDER BY created_at DESC, id DESC LIMIT 21`).
+		WithArgs(1, sqlmock.AnyArg(), 5).
+		WillReturnRows(rows)
+
+	repo := NewTodoRepo(db)
+	_, err = repo.List(context.Background(), 1, models.TodoFilter{Cursor: cursor, Limit: 20})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
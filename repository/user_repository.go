@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lib/pq"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+)
+
+// ErrEmailTaken is returned by Create when the email is already registered.
+var ErrEmailTaken = errors.New("email already registered")
+
+// uniqueViolation is the Postgres error code for a unique constraint
+// violation (here, the one on users.email).
+const uniqueViolation pq.ErrorCode = "23505"
+
+// UserRepository persists user accounts.
+type UserRepository interface {
+	Create(ctx context.Context, email, passwordHash string) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+}
+
+// UserRepo is a UserRepository backed by Postgres.
+type UserRepo struct {
+	db DB
+}
+
+// NewUserRepo constructs a UserRepo bound to db.
+func NewUserRepo(db DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) Create(ctx context.Context, email, passwordHash string) (models.User, error) {
+	var user models.User
+	user.Email = email
+	err := r.db.QueryRowContext(ctx,
+		"INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, email, created_at",
+		email, passwordHash,
+	).Scan(&user.ID, &user.Email, &user.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.User{}, ErrEmailTaken
+	}
+	return user, err
+}
+
+func (r *UserRepo) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, email, password_hash, created_at FROM users WHERE email = $1",
+		email,
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+	return user, err
+}
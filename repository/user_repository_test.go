@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestUserRepo_Create_DuplicateEmailMapsToErrEmailTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("dave@example.com", "hash").
+		WillReturnError(&pq.Error{Code: uniqueViolation, Message: "duplicate key value violates unique constraint \"users_email_key\""})
+
+	repo := NewUserRepo(db)
+	_, err = repo.Create(context.Background(), "dave@example.com", "hash")
+	if !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("want ErrEmailTaken, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUserRepo_Create_OtherPQErrorNotMisclassified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	wantErr := &pq.Error{Code: "23503", Message: "foreign key violation"}
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("dave@example.com", "hash").
+		WillReturnError(wantErr)
+
+	repo := NewUserRepo(db)
+	_, err = repo.Create(context.Background(), "dave@example.com", "hash")
+	if errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("want non-ErrEmailTaken error, got ErrEmailTaken")
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23503" {
+		t.Fatalf("want the original pq.Error to pass through, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUserRepo_Create_NoErrorReturnsUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	createdAt, err := time.Parse(time.RFC3339, "2026-01-02T03:04:05Z")
+	if err != nil {
+		t.Fatalf("time.Parse: %v", err)
+	}
+	rows := sqlmock.NewRows([]string{"id", "email", "created_at"}).
+		AddRow(1, "dave@example.com", createdAt)
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("dave@example.com", "hash").
+		WillReturnRows(rows)
+
+	repo := NewUserRepo(db)
+	user, err := repo.Create(context.Background(), "dave@example.com", "hash")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID != 1 || user.Email != "dave@example.com" {
+		t.Fatalf("got %+v, want id=1 email=dave@example.com", user)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+)
+
+// ErrInvalidCredentials is returned by Login when the email/password pair
+// does not match a known account.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// ErrMissingCredentials is returned by Authenticate when no token was supplied.
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// ErrInvalidToken is returned by Authenticate when the supplied token does
+// not match an active session.
+var ErrInvalidToken = errors.New("invalid credentials")
+
+// ErrMissingFields is returned by Register when the email or password is blank.
+var ErrMissingFields = errors.New("email and password are required")
+
+// AuthService implements registration, login, and token authentication.
+type AuthService struct {
+	users    repository.UserRepository
+	sessions repository.SessionRepository
+}
+
+// NewAuthService constructs an AuthService backed by the given repositories.
+func NewAuthService(users repository.UserRepository, sessions repository.SessionRepository) *AuthService {
+	return &AuthService{users: users, sessions: sessions}
+}
+
+func (s *AuthService) Register(ctx context.Context, input models.RegisterInput) (models.User, error) {
+	email := strings.TrimSpace(strings.ToLower(input.Email))
+	if email == "" || input.Password == "" {
+		return models.User{}, ErrMissingFields
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return s.users.Create(ctx, email, string(hash))
+}
+
+func (s *AuthService) Login(ctx context.Context, input models.LoginInput) (string, error) {
+	email := strings.TrimSpace(strings.ToLower(input.Email))
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if err == sql.ErrNoRows {
+		return "", ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.sessions.Create(ctx, token, user.ID); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Authenticate resolves a bearer token to a user ID.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (int, error) {
+	if token == "" {
+		return 0, ErrMissingCredentials
+	}
+
+	userID, err := s.sessions.UserIDForToken(ctx, token)
+	if err == sql.ErrNoRows {
+		return 0, ErrInvalidToken
+	}
+	return userID, err
+}
+
+// newToken generates a random, URL-safe session token.
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/repository/repotest"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+func newTestAuthService() *services.AuthService {
+	return services.NewAuthService(repotest.NewFakeUserRepo(), repotest.NewFakeSessionRepo())
+}
+
+func TestAuthService_RegisterAndLogin(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	user, err := svc.Register(ctx, models.RegisterInput{Email: "Alice@Example.com", Password: "hunter2"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if user.Email != "alice@example.com" {
+		t.Errorf("email = %q, want normalized lowercase", user.Email)
+	}
+	if user.PasswordHash == "" || user.PasswordHash == "hunter2" {
+		t.Errorf("password hash not set or stored in plaintext: %q", user.PasswordHash)
+	}
+
+	token, err := svc.Login(ctx, models.LoginInput{Email: "alice@example.com", Password: "hunter2"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if token == "" {
+		t.Fatal("Login returned empty token")
+	}
+
+	userID, err := svc.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if userID != user.ID {
+		t.Errorf("userID = %d, want %d", userID, user.ID)
+	}
+}
+
+func TestAuthService_RegisterDuplicateEmail(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	if _, err := svc.Register(ctx, models.RegisterInput{Email: "bob@example.com", Password: "pw"}); err != nil {
+		t.Fatalf("first Register: %v", err)
+	}
+	if _, err := svc.Register(ctx, models.RegisterInput{Email: "bob@example.com", Password: "other"}); !errors.Is(err, repository.ErrEmailTaken) {
+		t.Fatalf("want ErrEmailTaken, got %v", err)
+	}
+}
+
+func TestAuthService_RegisterMissingFields(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	cases := []models.RegisterInput{
+		{Email: "", Password: "pw"},
+		{Email: "nopw@example.com", Password: ""},
+	}
+	for _, in := range cases {
+		if _, err := svc.Register(ctx, in); !errors.Is(err, services.ErrMissingFields) {
+			t.Errorf("Register(%+v): want ErrMissingFields, got %v", in, err)
+		}
+	}
+}
+
+func TestAuthService_LoginWrongPassword(t *testing.T) {
+	svc := newTestAuthService()
+	ctx := context.Background()
+
+	if _, err := svc.Register(ctx, models.RegisterInput{Email: "carol@example.com", Password: "correct"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := svc.Login(ctx, models.LoginInput{Email: "carol@example.com", Password: "wrong"}); !errors.Is(err, services.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthService_LoginUnknownEmail(t *testing.T) {
+	svc := newTestAuthService()
+
+	if _, err := svc.Login(context.Background(), models.LoginInput{Email: "nobody@example.com", Password: "pw"}); !errors.Is(err, services.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthService_AuthenticateMissingToken(t *testing.T) {
+	svc := newTestAuthService()
+
+	if _, err := svc.Authenticate(context.Background(), ""); !errors.Is(err, services.ErrMissingCredentials) {
+		t.Fatalf("want ErrMissingCredentials, got %v", err)
+	}
+}
+
+func TestAuthService_AuthenticateInvalidToken(t *testing.T) {
+	svc := newTestAuthService()
+
+	if _, err := svc.Authenticate(context.Background(), "not-a-real-token"); !errors.Is(err, services.ErrInvalidToken) {
+		t.Fatalf("want ErrInvalidToken, got %v", err)
+	}
+}
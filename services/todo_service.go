@@ -0,0 +1,86 @@
+// Package services holds the business logic that sits between HTTP
+// handlers and the repository layer: validation and transaction
+// boundaries.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+)
+
+// ErrTitleRequired is returned by Create when the title is empty. Update
+// does not check the title.
+var ErrTitleRequired = errors.New("title is required")
+
+// ErrDueDateInPast is returned when a todo is created or would be updated
+// with a due date that has already passed.
+var ErrDueDateInPast = errors.New("due_date must be in the future")
+
+// TodoService implements the todo business logic on top of a TodoRepository.
+type TodoService struct {
+	db    *sql.DB
+	repos func(repository.DB) repository.TodoRepository
+}
+
+// NewTodoService constructs a TodoService. newRepo lets callers swap in a
+// fake TodoRepository for unit tests without touching the database.
+func NewTodoService(db *sql.DB, newRepo func(repository.DB) repository.TodoRepository) *TodoService {
+	return &TodoService{db: db, repos: newRepo}
+}
+
+func (s *TodoService) List(ctx context.Context, userID int, filter models.TodoFilter) (models.TodoPage, error) {
+	return s.repos(s.db).List(ctx, userID, filter)
+}
+
+func (s *TodoService) Get(ctx context.Context, userID, id int) (models.Todo, error) {
+	return s.repos(s.db).GetByID(ctx, userID, id)
+}
+
+func (s *TodoService) Create(ctx context.Context, userID int, input models.TodoCreate) (models.Todo, error) {
+	if strings.TrimSpace(input.Title) == "" {
+		return models.Todo{}, ErrTitleRequired
+	}
+	if input.DueDate != nil && input.DueDate.Before(time.Now()) {
+		return models.Todo{}, ErrDueDateInPast
+	}
+
+	var todo models.Todo
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		created, err := s.repos(tx).Create(ctx, userID, input)
+		if err != nil {
+			return err
+		}
+		todo = created
+		return nil
+	})
+	return todo, err
+}
+
+func (s *TodoService) Update(ctx context.Context, userID, id int, input models.TodoUpdate, ifMatch *time.Time) (models.Todo, error) {
+	if input.DueDate != nil && input.DueDate.Before(time.Now()) {
+		return models.Todo{}, ErrDueDateInPast
+	}
+
+	var todo models.Todo
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		updated, err := s.repos(tx).Update(ctx, userID, id, input, ifMatch)
+		if err != nil {
+			return err
+		}
+		todo = updated
+		return nil
+	})
+	return todo, err
+}
+
+func (s *TodoService) Delete(ctx context.Context, userID, id int, ifMatch *time.Time) error {
+	return repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		return s.repos(tx).Delete(ctx, userID, id, ifMatch)
+	})
+}
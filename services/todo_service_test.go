@@ -0,0 +1,187 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sharipovr/todo-crud-postgres/models"
+	"github.com/sharipovr/todo-crud-postgres/repository"
+	"github.com/sharipovr/todo-crud-postgres/repository/repotest"
+	"github.com/sharipovr/todo-crud-postgres/services"
+)
+
+// newTestService wires a TodoService to fake so repository behavior is
+// in-memory, while db only needs to satisfy WithTx's begin/commit/rollback.
+func newTestService(t *testing.T, fake *repotest.FakeTodoRepo) *services.TodoService {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	return services.NewTodoService(db, func(repository.DB) repository.TodoRepository { return fake })
+}
+
+func TestTodoService_Update_NotFound(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	svc := newTestService(t, fake)
+
+	title := "updated"
+	_, err := svc.Update(context.Background(), 1, 999, models.TodoUpdate{Title: &title}, nil)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestTodoService_Update_PreconditionFailed(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	seeded := fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	svc := newTestService(t, fake)
+
+	stale := seeded.UpdatedAt.Add(-time.Minute)
+	title := "updated"
+	_, err := svc.Update(context.Background(), 1, 1, models.TodoUpdate{Title: &title}, &stale)
+	if !errors.Is(err, repository.ErrPreconditionFailed) {
+		t.Fatalf("want ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestTodoService_Delete_NotFound(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	svc := newTestService(t, fake)
+
+	err := svc.Delete(context.Background(), 1, 999, nil)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestTodoService_Delete_PreconditionFailed(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	seeded := fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "original", UpdatedAt: time.Now()})
+	svc := newTestService(t, fake)
+
+	stale := seeded.UpdatedAt.Add(-time.Minute)
+	if err := svc.Delete(context.Background(), 1, 1, &stale); !errors.Is(err, repository.ErrPreconditionFailed) {
+		t.Fatalf("want ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestTodoService_List_FiltersByOnlyAndTag(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "done", Completed: true, Tags: []string{"work"}, CreatedAt: time.Now()})
+	fake.Seed(models.Todo{ID: 2, UserID: 1, Title: "todo", Completed: false, Tags: []string{"home"}, CreatedAt: time.Now()})
+	fake.Seed(models.Todo{ID: 3, UserID: 2, Title: "other user", Completed: false, CreatedAt: time.Now()})
+	svc := newTestService(t, fake)
+
+	page, err := svc.List(context.Background(), 1, models.TodoFilter{Only: "active"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 2 {
+		t.Fatalf("Only=active: got %+v, want just todo #2", page.Items)
+	}
+
+	page, err = svc.List(context.Background(), 1, models.TodoFilter{Tag: "work"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 1 {
+		t.Fatalf("Tag=work: got %+v, want just todo #1", page.Items)
+	}
+}
+
+func TestTodoService_List_PaginatesWithLimitAndOffset(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	base := time.Now()
+	for i := 1; i <= 3; i++ {
+		fake.Seed(models.Todo{ID: i, UserID: 1, Title: "t", CreatedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+	svc := newTestService(t, fake)
+
+	page, err := svc.List(context.Background(), 1, models.TodoFilter{Limit: 2, SortColumn: "created_at"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != 1 || page.Items[1].ID != 2 {
+		t.Fatalf("page 1 = %+v, want todos #1,#2", page.Items)
+	}
+
+	page, err = svc.List(context.Background(), 1, models.TodoFilter{Limit: 2, Offset: 2, SortColumn: "created_at"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 3 {
+		t.Fatalf("page 2 = %+v, want just todo #3", page.Items)
+	}
+}
+
+func TestTodoService_List_CursorPagination(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	base := time.Now()
+	for i := 1; i <= 3; i++ {
+		fake.Seed(models.Todo{ID: i, UserID: 1, Title: "t", CreatedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+	svc := newTestService(t, fake)
+
+	// Cursor mode orders by (created_at, id) descending; a cursor placed
+	// after every seeded row's created_at fetches from the newest item.
+	start := repository.EncodeCursor(base.Add(time.Hour), 0)
+
+	page, err := svc.List(context.Background(), 1, models.TodoFilter{Limit: 2, Cursor: start})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != 3 || page.Items[1].ID != 2 {
+		t.Fatalf("first cursor page = %+v, want todos #3,#2 (newest first)", page.Items)
+	}
+	if page.NextCursor == "" {
+		t.Fatal("want a NextCursor since a third todo remains")
+	}
+
+	page, err = svc.List(context.Background(), 1, models.TodoFilter{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("List with cursor: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 1 {
+		t.Fatalf("second cursor page = %+v, want just todo #1", page.Items)
+	}
+	if page.NextCursor != "" {
+		t.Errorf("NextCursor = %q, want empty once exhausted", page.NextCursor)
+	}
+}
+
+func TestTodoService_List_IncludeTotal(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	fake.Seed(models.Todo{ID: 1, UserID: 1, Title: "t", CreatedAt: time.Now()})
+	fake.Seed(models.Todo{ID: 2, UserID: 1, Title: "t", CreatedAt: time.Now()})
+	svc := newTestService(t, fake)
+
+	page, err := svc.List(context.Background(), 1, models.TodoFilter{Limit: 1, IncludeTotal: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if page.Total == nil || *page.Total != 2 {
+		t.Fatalf("Total = %v, want 2", page.Total)
+	}
+}
+
+func TestTodoService_List_InvalidSortColumn(t *testing.T) {
+	fake := repotest.NewFakeTodoRepo()
+	svc := newTestService(t, fake)
+
+	if _, err := svc.List(context.Background(), 1, models.TodoFilter{SortColumn: "password_hash"}); !errors.Is(err, repository.ErrInvalidSortColumn) {
+		t.Fatalf("want ErrInvalidSortColumn, got %v", err)
+	}
+}